@@ -0,0 +1,55 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package aw
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+)
+
+// itemJSON is the Alfred Script Filter JSON representation of an Item.
+type itemJSON struct {
+	Title        string            `json:"title"`
+	Subtitle     string            `json:"subtitle,omitempty"`
+	UID          string            `json:"uid,omitempty"`
+	Arg          string            `json:"arg,omitempty"`
+	Valid        bool              `json:"valid"`
+	Autocomplete string            `json:"autocomplete,omitempty"`
+	Match        string            `json:"match,omitempty"`
+	Icon         *Icon             `json:"icon,omitempty"`
+	Vars         map[string]string `json:"variables,omitempty"`
+}
+
+// send writes items to Alfred as Script Filter JSON and exits. It's the
+// terminal step of SendFeedback.
+func send(items []*Item) {
+	out := struct {
+		Items []itemJSON `json:"items"`
+	}{}
+	for _, it := range items {
+		out.Items = append(out.Items, itemJSON{
+			Title:        it.title,
+			Subtitle:     it.subtitle,
+			UID:          it.uid,
+			Arg:          it.arg,
+			Valid:        it.valid,
+			Autocomplete: it.autocomplete,
+			Match:        it.match,
+			Icon:         it.icon,
+			Vars:         it.vars,
+		})
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		log.Fatalf("marshal feedback: %v", err)
+	}
+	fmt.Println(string(data))
+	os.Exit(0)
+}
@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package aw
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// systemIconDir is where OS X/macOS keeps its built-in .icns files. It's a
+// var, not a const, so tests can point it at a temp directory instead of
+// stat'ing the real filesystem.
+var systemIconDir = "/System/Library/CoreServices/CoreTypes.bundle/Contents/Resources"
+
+// genericIconName is the .icns file every fallback chain ends in if none of
+// its candidates exist: it's the one icon file guaranteed to ship with every
+// macOS version AwGo supports.
+const genericIconName = "ToolbarInfo"
+
+// IconResolver maps the logical names used by systemIcon() to a chain of
+// candidate .icns files, falling back through the chain until it finds one
+// that actually exists on the running version of macOS. Some of the icons
+// Alfred-Workflow (and by extension this library) has always assumed exist
+// were removed from CoreTypes.bundle in macOS 13, so a hard-coded path can
+// silently point at nothing.
+//
+// Resolution happens once per icon name and the result is memoized, so
+// IconResolver only touches disk the first time each icon is needed.
+type IconResolver struct {
+	mu        sync.Mutex
+	fallbacks map[string][]string
+	resolved  map[string]string
+}
+
+// NewIconResolver creates an initialised IconResolver.
+func NewIconResolver() *IconResolver {
+	return &IconResolver{
+		fallbacks: map[string][]string{},
+		resolved:  map[string]string{},
+	}
+}
+
+// resolver is the resolver used by systemIcon() and RegisterIconFallback().
+var resolver = NewIconResolver()
+
+// register stores the fallback chain for name and drops any memoized result,
+// so the next resolve() re-checks disk with the new candidates.
+func (r *IconResolver) register(name string, candidates ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallbacks[name] = candidates
+	delete(r.resolved, name)
+}
+
+// resolve returns the path of the first candidate in name's fallback chain
+// that exists on disk, memoizing the result. If nothing in the chain exists,
+// it falls back to IconInfo's file, which ships with every version of macOS
+// AwGo supports.
+func (r *IconResolver) resolve(name string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if path, ok := r.resolved[name]; ok {
+		return path
+	}
+
+	candidates := r.fallbacks[name]
+	if len(candidates) == 0 {
+		candidates = []string{name}
+	}
+	// The generic icon is always the last resort, whether or not a chain
+	// was explicitly registered for name.
+	candidates = append(candidates, genericIconName)
+
+	path := fmt.Sprintf("%s/%s.icns", systemIconDir, genericIconName)
+	for _, c := range candidates {
+		p := fmt.Sprintf("%s/%s.icns", systemIconDir, c)
+		if _, err := os.Stat(p); err == nil {
+			path = p
+			break
+		}
+	}
+
+	r.resolved[name] = path
+	return path
+}
+
+// Paths returns the resolved path for every icon name that has a
+// registered fallback chain (the built-in chains and any added with
+// RegisterIconFallback), keyed by name. Used by the
+// "workflow:icons" Magic Action to help users report broken icons.
+func (r *IconResolver) Paths() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	paths := make(map[string]string, len(r.fallbacks))
+	for name := range r.fallbacks {
+		paths[name] = r.resolved[name]
+	}
+	return paths
+}
+
+// RegisterIconFallback registers a fallback chain of candidate .icns names
+// for iconVar (one of the IconXYZ package variables, or an Icon created by
+// a workflow for its own theming) and immediately re-resolves iconVar.Value
+// against it.
+//
+// candidates are tried in order, stat'd against
+// /System/Library/CoreServices/CoreTypes.bundle/Contents/Resources; the
+// first one that exists is used. If none exist, iconVar falls back to
+// IconInfo.
+//
+// Workflows can use this to extend or override the chains AwGo ships with,
+// e.g. to add their own themed replacement for an icon that has gone
+// missing on newer macOS versions:
+//
+//    aw.RegisterIconFallback(aw.IconWarning, "AlertCautionIcon", "AlertCautionBadgeIcon")
+func RegisterIconFallback(iconVar *Icon, candidates ...string) {
+	if len(candidates) == 0 {
+		return
+	}
+	resolver.register(candidates[0], candidates...)
+	iconVar.Value = resolver.resolve(candidates[0])
+	iconVar.Type = IconTypeImageFile
+}
@@ -0,0 +1,79 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package aw
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withSystemIconDir points systemIconDir at a fresh temp dir containing the
+// given .icns filenames (without extension), and returns a cleanup func.
+func withSystemIconDir(t *testing.T, files ...string) func() {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "awgo-icons")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	for _, f := range files {
+		p := filepath.Join(dir, f+".icns")
+		if err := ioutil.WriteFile(p, []byte("icns"), 0600); err != nil {
+			t.Fatalf("write %s: %v", p, err)
+		}
+	}
+
+	prev := systemIconDir
+	systemIconDir = dir
+	return func() {
+		systemIconDir = prev
+		os.RemoveAll(dir)
+	}
+}
+
+func TestIconResolverFallsBackThroughChain(t *testing.T) {
+	defer withSystemIconDir(t, genericIconName, "AlertCautionBadgeIcon")()
+
+	r := NewIconResolver()
+	r.register("AlertCautionIcon", "AlertCautionIcon", "AlertCautionBadgeIcon")
+
+	got := r.resolve("AlertCautionIcon")
+	want := filepath.Join(systemIconDir, "AlertCautionBadgeIcon.icns")
+	if got != want {
+		t.Errorf("resolve() = %q, want %q", got, want)
+	}
+}
+
+func TestIconResolverNoChainFallsBackToGeneric(t *testing.T) {
+	defer withSystemIconDir(t, genericIconName)()
+
+	r := NewIconResolver()
+
+	got := r.resolve("SomeIconThatDoesNotExist")
+	want := filepath.Join(systemIconDir, genericIconName+".icns")
+	if got != want {
+		t.Errorf("resolve() = %q, want %q", got, want)
+	}
+}
+
+func TestIconResolverMemoizes(t *testing.T) {
+	cleanup := withSystemIconDir(t, "Clock")
+	defer cleanup()
+
+	r := NewIconResolver()
+	first := r.resolve("Clock")
+
+	// Removing the file after the first resolve must not change the
+	// memoized result.
+	os.RemoveAll(systemIconDir)
+
+	second := r.resolve("Clock")
+	if first != second {
+		t.Errorf("resolve() changed after memoization: %q != %q", first, second)
+	}
+}
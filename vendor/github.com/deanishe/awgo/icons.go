@@ -6,8 +6,6 @@
 
 package aw
 
-import "fmt"
-
 // IconType specifies the type of an aw.Icon struct. It can be an image file,
 // the icon of a file, e.g. an application's icon, or the icon for a UTI.
 type IconType string
@@ -58,6 +56,11 @@ var (
 	IconUser      *Icon // UserIcon.icns
 	IconWarning   *Icon // AlertCautionIcon.icns
 	IconWeb       *Icon // BookmarkIcon.icns
+
+	// IconUpdateAvailable is shown on the "update available" item appended
+	// to feedback by Workflow.NotifyUpdate. It defaults to IconInfo;
+	// override it with WithUpdateBadge to use your own asset.
+	IconUpdateAvailable *Icon
 )
 
 // Icon represents the icon for an Item.
@@ -91,17 +94,38 @@ type Icon struct {
 	Type  IconType `json:"type,omitempty"` // "fileicon", "filetype" or ""
 }
 
+// systemIcon builds an Icon for one of the built-in OS X/macOS system icons,
+// resolving it against IconResolver's fallback chain for that name (falling
+// back to the plain, single-candidate chain of just filename if no chain has
+// been registered for it). This keeps icons working on macOS versions that
+// have removed or renamed individual .icns files from CoreTypes.bundle.
 func systemIcon(filename string) *Icon {
-	icon := &Icon{}
-	var path string
-	path = fmt.Sprintf(
-		"/System/Library/CoreServices/CoreTypes.bundle/Contents/Resources/%s.icns", filename)
-	icon.Value = path
-	icon.Type = IconTypeImageFile
-	return icon
+	return &Icon{
+		Value: resolver.resolve(filename),
+		Type:  IconTypeImageFile,
+	}
+}
+
+// brokenSystemIcons are the known-missing icons, each mapped to the
+// replacement(s) to try before falling back to the generic icon. macOS 13
+// removed or renamed several files that were always present before, so any
+// icon that has been reported broken gets an entry here; everything else
+// still goes through IconResolver's single-candidate default, which falls
+// back to the generic icon if it's missing too.
+var brokenSystemIcons = map[string][]string{
+	"AlertCautionIcon": {"AlertCautionBadgeIcon"},
+	"AlertStopIcon":    {"AlertStopBadgeIcon"},
+	"AlertNoteIcon":    {"AlertNoteBadgeIcon"},
 }
 
 func init() {
+	// Chains for icons known to have disappeared from CoreTypes.bundle on
+	// newer macOS releases. Workflows can extend or override these with
+	// RegisterIconFallback.
+	for name, fallbacks := range brokenSystemIcons {
+		resolver.register(name, append([]string{name}, fallbacks...)...)
+	}
+
 	IconWorkflow = &Icon{"icon.png", ""}
 	IconAccount = systemIcon("Accounts")
 	IconBurn = systemIcon("BurningIcon")
@@ -126,4 +150,6 @@ func init() {
 	IconUser = systemIcon("UserIcon")
 	IconWarning = systemIcon("AlertCautionIcon")
 	IconWeb = systemIcon("BookmarkIcon")
+
+	IconUpdateAvailable = IconInfo
 }
@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package aw
+
+import "strings"
+
+// Item is a single Script Filter result. Create one with Workflow.NewItem
+// (or the package-level NewItem, which adds to the default Workflow) and
+// configure it with the fluent setters below, e.g.:
+//
+//    wf.NewItem("Info.plist").
+//        Subtitle("~/Library/Application Support/Alfred/...").
+//        Valid(true).
+//        Icon(aw.IconInfo)
+type Item struct {
+	title        string
+	subtitle     string
+	uid          string
+	arg          string
+	valid        bool
+	autocomplete string
+	match        string
+	icon         *Icon
+	vars         map[string]string
+}
+
+// Subtitle sets the Item's subtitle.
+func (it *Item) Subtitle(s string) *Item { it.subtitle = s; return it }
+
+// Valid sets whether the Item can be actioned in Alfred.
+func (it *Item) Valid(v bool) *Item { it.valid = v; return it }
+
+// Icon sets the Item's icon.
+func (it *Item) Icon(icon *Icon) *Item { it.icon = icon; return it }
+
+// UID sets the Item's unique ID, which Alfred uses to remember the
+// user's choice for ranking future results.
+func (it *Item) UID(s string) *Item { it.uid = s; return it }
+
+// Arg sets the value passed to the next action in the workflow when
+// the Item is actioned.
+func (it *Item) Arg(s string) *Item { it.arg = s; return it }
+
+// Autocomplete sets the value Alfred inserts into the query when the
+// user TABs this Item.
+func (it *Item) Autocomplete(s string) *Item { it.autocomplete = s; return it }
+
+// Match sets the text Filter matches the user's query against, instead
+// of the Item's title.
+func (it *Item) Match(s string) *Item { it.match = s; return it }
+
+// Var sets a workflow variable that is passed to the next action in the
+// workflow when this Item is actioned (or back into the workflow, for a
+// Script Filter feeding itself, e.g. to persist a session ID).
+func (it *Item) Var(key, value string) *Item {
+	if it.vars == nil {
+		it.vars = map[string]string{}
+	}
+	it.vars[key] = value
+	return it
+}
+
+// matches reports whether the Item's text contains query, ignoring case,
+// using Match if it's set, falling back to the title. It is a plain
+// substring test, not a fuzzy match.
+func (it *Item) matches(query string) bool {
+	haystack := it.match
+	if haystack == "" {
+		haystack = it.title
+	}
+	return strings.Contains(strings.ToLower(haystack), strings.ToLower(query))
+}
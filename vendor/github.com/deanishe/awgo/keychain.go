@@ -0,0 +1,60 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package aw
+
+import (
+	"os"
+	"strings"
+
+	// Imported under a local name so the rest of this file can refer to
+	// it as `backend`.
+	backend "github.com/deanishe/awgo/keychain"
+)
+
+// Keychainer is the interface returned by Workflow.Keychain(): normally a
+// real *keychain.Keychain backed by macOS `security(1)`, or a
+// keychain.MemoryStore when useMemoryKeychain() says a real keychain
+// isn't available (tests, CI).
+type Keychainer = backend.Store
+
+// useMemoryKeychain reports whether Workflow.Keychain() should hand back
+// an in-memory fallback instead of shelling out to `security`. True
+// under `go test` and on CI, where there's no real keychain (or where
+// shelling out to `security` would require user interaction).
+func useMemoryKeychain() bool {
+	if os.Getenv("CI") != "" {
+		return true
+	}
+	// testing.Testing() was only added in Go 1.21; os.Args[0] ending in
+	// ".test" is the portable way to detect a test binary.
+	return strings.HasSuffix(os.Args[0], ".test")
+}
+
+// Keychain returns the Workflow's keychain store, scoped to its bundle
+// ID. Use it to store secrets (such as OAuth refresh tokens) that
+// shouldn't be written in plain text to the cache or data directory.
+func (wf *Workflow) Keychain() Keychainer {
+	if wf.keychain == nil {
+		if useMemoryKeychain() {
+			wf.keychain = backend.NewMemoryStore()
+		} else {
+			wf.keychain = backend.New(wf.BundleID())
+		}
+	}
+	return wf.keychain
+}
+
+// Keychain returns the default Workflow's keychain store.
+func Keychain() Keychainer { return wf.Keychain() }
+
+// Deletes all entries in the workflow's keychain.
+type clearAuthMagic struct{}
+
+func (a clearAuthMagic) Keyword() string     { return "delauth" }
+func (a clearAuthMagic) Description() string { return "Delete workflow's saved credentials" }
+func (a clearAuthMagic) RunText() string     { return "Deleted workflow's saved credentials" }
+func (a clearAuthMagic) Run() error          { return Keychain().Clear() }
@@ -0,0 +1,153 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+// Package keychain stores secrets (such as OAuth tokens) in the macOS
+// login keychain via the `security(1)` command-line tool, scoped by a
+// workflow's bundle ID. This keeps secrets out of the plain-text cache
+// and data directories AwGo otherwise uses for persistence.
+//
+// A non-macOS fallback (MemoryStore) is provided for tests and CI, where the
+// keychain isn't available.
+package keychain
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Store is the interface implemented by Keychain and its in-memory
+// fallback, so callers (and tests) can swap one for the other.
+type Store interface {
+	Set(key, value string) error
+	Get(key string) (string, error)
+	Delete(key string) error
+	Clear() error
+}
+
+// Keychain reads and writes generic passwords in the macOS login keychain,
+// scoped to a single workflow via its bundle ID.
+type Keychain struct {
+	BundleID string
+}
+
+// New creates a Keychain scoped to bundleID.
+func New(bundleID string) *Keychain {
+	return &Keychain{BundleID: bundleID}
+}
+
+// Set stores value under key in the keychain, overwriting any existing
+// entry for key.
+func (kc *Keychain) Set(key, value string) error {
+	// Remove any existing entry first: `security add-generic-password`
+	// fails if one already exists, and there's no "upsert" flag.
+	_ = kc.Delete(key)
+
+	cmd := exec.Command("security", "add-generic-password",
+		"-a", key,
+		"-s", kc.BundleID,
+		"-w", value,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("set %q in keychain: %s: %w", key, bytes.TrimSpace(out), err)
+	}
+	return nil
+}
+
+// Get retrieves the value stored under key. It returns an error if no
+// such entry exists.
+func (kc *Keychain) Get(key string) (string, error) {
+	cmd := exec.Command("security", "find-generic-password",
+		"-a", key,
+		"-s", kc.BundleID,
+		"-w",
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("get %q from keychain: %w", key, err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// Delete removes the entry stored under key. It is not an error to
+// delete a key that doesn't exist.
+func (kc *Keychain) Delete(key string) error {
+	cmd := exec.Command("security", "delete-generic-password",
+		"-a", key,
+		"-s", kc.BundleID,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if strings.Contains(string(out), "could not be found") {
+			return nil
+		}
+		return fmt.Errorf("delete %q from keychain: %s: %w", key, bytes.TrimSpace(out), err)
+	}
+	return nil
+}
+
+// Clear removes every entry stored under kc.BundleID.
+func (kc *Keychain) Clear() error {
+	for {
+		find := exec.Command("security", "find-generic-password", "-s", kc.BundleID)
+		if err := find.Run(); err != nil {
+			// No (more) matching entries.
+			return nil
+		}
+		del := exec.Command("security", "delete-generic-password", "-s", kc.BundleID)
+		if out, err := del.CombinedOutput(); err != nil {
+			return fmt.Errorf("clear keychain: %s: %w", bytes.TrimSpace(out), err)
+		}
+	}
+}
+
+// MemoryStore is an in-memory Store for use in tests, where a real
+// keychain isn't available (e.g. on CI).
+type MemoryStore struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: map[string]string{}}
+}
+
+// Set stores value under key.
+func (s *MemoryStore) Set(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = value
+	return nil
+}
+
+// Get retrieves the value stored under key, or an error if it's unset.
+func (s *MemoryStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[key]
+	if !ok {
+		return "", fmt.Errorf("no such key: %s", key)
+	}
+	return v, nil
+}
+
+// Delete removes the entry stored under key.
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+// Clear removes all entries.
+func (s *MemoryStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = map[string]string{}
+	return nil
+}
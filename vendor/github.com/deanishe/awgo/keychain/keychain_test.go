@@ -0,0 +1,68 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package keychain
+
+import "testing"
+
+// Compile-time check that both implementations satisfy Store.
+var (
+	_ Store = (*Keychain)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
+
+func TestMemoryStoreSetGet(t *testing.T) {
+	s := NewMemoryStore()
+
+	if _, err := s.Get("token"); err == nil {
+		t.Fatal("Get of unset key should error")
+	}
+
+	if err := s.Set("token", "s3cr3t"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	got, err := s.Get("token")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got != "s3cr3t" {
+		t.Errorf("Get() = %q, want %q", got, "s3cr3t")
+	}
+}
+
+func TestMemoryStoreDelete(t *testing.T) {
+	s := NewMemoryStore()
+	_ = s.Set("token", "s3cr3t")
+
+	if err := s.Delete("token"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get("token"); err == nil {
+		t.Fatal("Get after Delete should error")
+	}
+
+	// Deleting an already-absent key is not an error.
+	if err := s.Delete("token"); err != nil {
+		t.Fatalf("Delete of absent key: %v", err)
+	}
+}
+
+func TestMemoryStoreClear(t *testing.T) {
+	s := NewMemoryStore()
+	_ = s.Set("a", "1")
+	_ = s.Set("b", "2")
+
+	if err := s.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+
+	for _, key := range []string{"a", "b"} {
+		if _, err := s.Get(key); err == nil {
+			t.Errorf("Get(%q) after Clear should error", key)
+		}
+	}
+}
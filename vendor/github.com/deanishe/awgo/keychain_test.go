@@ -0,0 +1,31 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package aw
+
+import "testing"
+
+func TestWorkflowKeychainUsesMemoryStoreUnderTest(t *testing.T) {
+	wf := New()
+
+	kc := wf.Keychain()
+	if err := kc.Set("token", "s3cr3t"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, err := kc.Get("token")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got != "s3cr3t" {
+		t.Errorf("Get() = %q, want %q", got, "s3cr3t")
+	}
+
+	// A test binary (os.Args[0] ends in ".test") must never shell out to
+	// the real keychain.
+	if !useMemoryKeychain() {
+		t.Fatal("useMemoryKeychain() = false when running under `go test`")
+	}
+}
@@ -9,6 +9,7 @@
 package aw
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -16,6 +17,16 @@ import (
 	"strings"
 )
 
+// macOSVersion returns the output of `sw_vers -productVersion`, or "unknown"
+// if it can't be determined (e.g. when running tests on another OS).
+func macOSVersion() string {
+	out, err := exec.Command("sw_vers", "-productVersion").Output()
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(out))
+}
+
 // DefaultMagicPrefix is the default prefix for "magic" arguments.
 // This can be overriden with the MagicPrefix value in Options.
 const DefaultMagicPrefix = "workflow:"
@@ -23,12 +34,15 @@ const DefaultMagicPrefix = "workflow:"
 // Magic actions registered by default.
 var (
 	DefaultMagicActions = []MagicAction{
-		openLogMagic{},    // Opens log file
-		openCacheMagic{},  // Opens cache directory
-		clearCacheMagic{}, // Clears cache directory
-		openDataMagic{},   // Opens data directory
-		clearDataMagic{},  // Clears data directory
-		resetMagic{},      // Clears cache and data directories
+		openLogMagic{},      // Opens log file
+		openCacheMagic{},    // Opens cache directory
+		clearCacheMagic{},   // Clears cache directory
+		openDataMagic{},     // Opens data directory
+		clearDataMagic{},    // Clears data directory
+		resetMagic{},        // Clears cache and data directories
+		iconsMagic{},        // Shows resolved system icon paths and OS version
+		clearAuthMagic{},    // Clears keychain entries
+		clearSessionMagic{}, // Clears current session's cached data
 	}
 )
 
@@ -93,6 +107,47 @@ func (ma MagicActions) Args(args []string, prefix string) []string {
 	return args
 }
 
+// flagPrefix is prepended to a MagicAction's keyword to build its flag
+// name, e.g. the "log" action becomes the "-workflow-log" flag.
+const flagPrefix = "workflow-"
+
+// RegisterFlags installs a `-workflow-<keyword>` bool flag on fs for every
+// registered MagicAction, so workflows can be driven from the terminal
+// (e.g. by build scripts or shell hooks) without going through Alfred.
+// RunFlag calls this on a FlagSet of its own and parses the raw arguments
+// itself, so it needs neither fs nor a prior fs.Parse.
+func (ma MagicActions) RegisterFlags(fs *flag.FlagSet) {
+	for _, action := range ma {
+		name := flagPrefix + action.Keyword()
+		if fs.Lookup(name) != nil {
+			continue
+		}
+		fs.Bool(name, false, action.Description())
+	}
+}
+
+// RunFlag parses args against a FlagSet populated by RegisterFlags and, if
+// one of the `-workflow-<keyword>` flags was set, runs the corresponding
+// MagicAction and returns ran=true. Otherwise it returns the arguments
+// FlagSet didn't recognise as flags (fs.Args()) unchanged.
+func (ma MagicActions) RunFlag(args []string) (remaining []string, ran bool, err error) {
+	fs := flag.NewFlagSet("workflow", flag.ContinueOnError)
+	ma.RegisterFlags(fs)
+	if err = fs.Parse(args); err != nil {
+		return nil, false, err
+	}
+
+	for _, action := range ma {
+		f := fs.Lookup(flagPrefix + action.Keyword())
+		if f == nil || f.Value.String() != "true" {
+			continue
+		}
+		log.Printf(action.RunText())
+		return fs.Args(), true, action.Run()
+	}
+	return fs.Args(), false, nil
+}
+
 // MagicAction is a command that is called directly by AwGo (i.e. your workflow
 // code is not run) if its keyword is passed in a user query. Magic Actions are
 // mainly aimed at making debugging and supporting users easier (via the
@@ -128,6 +183,10 @@ func (ma MagicActions) Args(args []string, prefix string) []string {
 // to Alfred, filtered by the user's query. Hitting TAB or RETURN on
 // an item will run it.
 //
+// Magic Actions can also be run from the terminal, without going through
+// Alfred at all, via RegisterFlags/RunFlag, which expose each action as a
+// `-workflow-<keyword>` command-line flag.
+//
 //
 // The built-in magic actions are:
 //
@@ -139,6 +198,9 @@ func (ma MagicActions) Args(args []string, prefix string) []string {
 //    <prefix>deldata   | Delete everything in the workflow's data directory.
 //    <prefix>delcache  | Delete everything in the workflow's cache directory.
 //    <prefix>reset     | Delete everything in the workflow's data and cache directories.
+//    <prefix>delauth   | Delete everything in the workflow's keychain.
+//    <prefix>icons     | Log resolved icon paths and macOS version.
+//    <prefix>session   | Delete the current Alfred session's cached data.
 //    <prefix>help      | Open help URL in default browser.
 //                      | Only registered if you have set a HelpURL.
 //    <prefix>update    | Check for updates and install a newer version of the workflow
@@ -209,6 +271,21 @@ func (a resetMagic) Description() string { return "Delete all saved and cached w
 func (a resetMagic) RunText() string     { return "Deleted workflow saved and cached data" }
 func (a resetMagic) Run() error          { return Reset() }
 
+// Logs the resolved paths of all icons with a registered fallback chain,
+// along with the running macOS version, so users can report broken icons.
+type iconsMagic struct{}
+
+func (a iconsMagic) Keyword() string     { return "icons" }
+func (a iconsMagic) Description() string { return "Show resolved icon paths and macOS version" }
+func (a iconsMagic) RunText() string     { return "Logging resolved icon paths…" }
+func (a iconsMagic) Run() error {
+	log.Printf("macOS version: %s", macOSVersion())
+	for name, path := range resolver.Paths() {
+		log.Printf("%s -> %s", name, path)
+	}
+	return nil
+}
+
 // Opens URL in default browser.
 type helpMagic struct {
 	URL string
@@ -0,0 +1,134 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package aw
+
+import (
+	"flag"
+	"testing"
+)
+
+// fakeMagic is a MagicAction that records whether it was run.
+type fakeMagic struct {
+	keyword string
+	ran     *bool
+}
+
+func (a fakeMagic) Keyword() string     { return a.keyword }
+func (a fakeMagic) Description() string { return "fake action: " + a.keyword }
+func (a fakeMagic) RunText() string     { return "Running " + a.keyword + "…" }
+func (a fakeMagic) Run() error          { *a.ran = true; return nil }
+
+func newFakeActions(keywords ...string) (MagicActions, map[string]*bool) {
+	ma := MagicActions{}
+	ran := map[string]*bool{}
+	for _, kw := range keywords {
+		ranFlag := new(bool)
+		ran[kw] = ranFlag
+		ma.Register(fakeMagic{keyword: kw, ran: ranFlag})
+	}
+	return ma, ran
+}
+
+func TestRegisterFlagsRegistersOneFlagPerAction(t *testing.T) {
+	ma, _ := newFakeActions("log", "cache", "reset")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	ma.RegisterFlags(fs)
+
+	for kw := range ma {
+		name := flagPrefix + kw
+		if fs.Lookup(name) == nil {
+			t.Errorf("RegisterFlags didn't register -%s", name)
+		}
+	}
+}
+
+func TestRegisterFlagsCalledTwice(t *testing.T) {
+	ma, _ := newFakeActions("log", "cache")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	ma.RegisterFlags(fs)
+	ma.RegisterFlags(fs) // must not panic on already-registered flags
+
+	for kw := range ma {
+		name := flagPrefix + kw
+		if fs.Lookup(name) == nil {
+			t.Errorf("-%s missing after second RegisterFlags call", name)
+		}
+	}
+}
+
+func TestRunFlagNoneSet(t *testing.T) {
+	ma, ran := newFakeActions("log", "cache")
+
+	remaining, didRun, err := ma.RunFlag([]string{"query", "terms"})
+	if err != nil {
+		t.Fatalf("RunFlag: %v", err)
+	}
+	if didRun {
+		t.Error("RunFlag reported ran=true with no flags set")
+	}
+	for kw, ranFlag := range ran {
+		if *ranFlag {
+			t.Errorf("action %q ran unexpectedly", kw)
+		}
+	}
+	if len(remaining) != 2 || remaining[0] != "query" || remaining[1] != "terms" {
+		t.Errorf("remaining = %v, want [query terms]", remaining)
+	}
+}
+
+func TestRunFlagOneSet(t *testing.T) {
+	ma, ran := newFakeActions("log", "cache")
+
+	_, didRun, err := ma.RunFlag([]string{"-" + flagPrefix + "log"})
+	if err != nil {
+		t.Fatalf("RunFlag: %v", err)
+	}
+	if !didRun {
+		t.Fatal("RunFlag reported ran=false with -workflow-log set")
+	}
+	if !*ran["log"] {
+		t.Error("log action did not run")
+	}
+	if *ran["cache"] {
+		t.Error("cache action ran, but its flag wasn't set")
+	}
+}
+
+func TestRunFlagMultipleSet(t *testing.T) {
+	ma, ran := newFakeActions("log", "cache")
+
+	_, didRun, err := ma.RunFlag([]string{
+		"-" + flagPrefix + "log",
+		"-" + flagPrefix + "cache",
+	})
+	if err != nil {
+		t.Fatalf("RunFlag: %v", err)
+	}
+	if !didRun {
+		t.Fatal("RunFlag reported ran=false with two flags set")
+	}
+
+	ranCount := 0
+	for _, ranFlag := range ran {
+		if *ranFlag {
+			ranCount++
+		}
+	}
+	if ranCount != 1 {
+		t.Errorf("%d actions ran, want exactly 1 (RunFlag runs the first match)", ranCount)
+	}
+}
+
+func TestRunFlagParseError(t *testing.T) {
+	ma, _ := newFakeActions("log")
+
+	if _, _, err := ma.RunFlag([]string{"-not-a-registered-flag"}); err == nil {
+		t.Fatal("RunFlag didn't return an error for an unrecognised flag")
+	}
+}
@@ -0,0 +1,167 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package aw
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultSessionName is the name of the Alfred workflow variable Session
+// uses to persist its ID between script runs within a single Alfred
+// session.
+const DefaultSessionName = "AW_SESSION_ID"
+
+// Session scopes cache entries to the lifetime of a single Alfred
+// session, i.e. until the user closes Alfred's window. This lets a
+// Script Filter cache expensive lookups (such as a Google Calendar API
+// call) across keystrokes, while still invalidating them the next time
+// Alfred is opened.
+//
+// A session is identified by a UUID stored in an Alfred workflow
+// variable. The ID is generated on the first Script Filter run, and
+// Workflow.NewItem automatically sets it on every emitted Item, so Alfred
+// passes it back in on the next keystroke without the workflow author
+// having to remember to do it themselves.
+type Session struct {
+	Name string // workflow variable the session ID is stored in
+	dir  string // directory session cache files are stored in
+	id   string
+}
+
+// NewSession creates a Session that persists cache files under dir. If
+// the environment already holds a session ID (i.e. this isn't the first
+// run), it's reused; otherwise a new one is generated.
+func NewSession(dir string) *Session {
+	s := &Session{Name: DefaultSessionName, dir: dir}
+	s.id = os.Getenv(s.Name)
+	if s.id == "" {
+		s.id = newSessionID()
+	}
+	s.sweep()
+	return s
+}
+
+// newSessionID returns a random v4 UUID.
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// Extremely unlikely, but a unique-enough fallback is still
+		// better than a fixed ID shared by every session.
+		return fmt.Sprintf("%d", os.Getpid())
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// ID returns the session's ID.
+func (s *Session) ID() string { return s.id }
+
+// Var returns the workflow variable name and value that identifies this
+// session to Alfred. Workflow.NewItem calls this and sets the result on
+// every Item automatically, so the session ID round-trips to the next
+// keystroke without the caller having to remember to do it.
+func (s *Session) Var() (name, value string) { return s.Name, s.id }
+
+// cacheName returns the path of the session-scoped cache file for key.
+func (s *Session) cacheName(key string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.%s.json", s.id, key))
+}
+
+// Load reads the session cache entry for key into v. It returns false if
+// no entry is cached, in which case v is untouched, or if it can't be
+// decoded, in which case v may have been partly filled.
+func (s *Session) Load(key string, v interface{}) bool {
+	if s.dir == "" {
+		return false
+	}
+	data, err := ioutil.ReadFile(s.cacheName(key))
+	if err != nil {
+		return false
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false
+	}
+	return true
+}
+
+// Store saves v as JSON under the session cache entry for key.
+func (s *Session) Store(key string, v interface{}) error {
+	if s.dir == "" {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal session cache %q: %w", key, err)
+	}
+	if err := ioutil.WriteFile(s.cacheName(key), data, 0600); err != nil {
+		return fmt.Errorf("write session cache %q: %w", key, err)
+	}
+	return nil
+}
+
+// Clear removes all cache files for the current session.
+func (s *Session) Clear() error {
+	if s.dir == "" {
+		return nil
+	}
+	matches, err := filepath.Glob(filepath.Join(s.dir, s.id+".*.json"))
+	if err != nil {
+		return err
+	}
+	for _, p := range matches {
+		if err := os.Remove(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sweep deletes cache files belonging to previous sessions, so stale
+// session data doesn't accumulate once Alfred's window is closed and
+// reopened.
+func (s *Session) sweep() {
+	if s.dir == "" {
+		// No cache directory configured (e.g. Workflow created outside
+		// Alfred, as in tests) - nothing to sweep.
+		return
+	}
+	matches, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return
+	}
+	for _, p := range matches {
+		name := filepath.Base(p)
+		if strings.HasPrefix(name, s.id+".") {
+			continue
+		}
+		os.Remove(p)
+	}
+}
+
+// Session returns the Workflow's Session, creating it (and generating or
+// reusing its ID) on first call.
+func (wf *Workflow) Session() *Session {
+	if wf.session == nil {
+		wf.session = NewSession(wf.CacheDir())
+	}
+	return wf.session
+}
+
+// Clears the current session's cached data.
+type clearSessionMagic struct{}
+
+func (a clearSessionMagic) Keyword() string     { return "session" }
+func (a clearSessionMagic) Description() string { return "Delete session-scoped cached data" }
+func (a clearSessionMagic) RunText() string     { return "Deleted session-scoped cached data" }
+func (a clearSessionMagic) Run() error          { return wf.Session().Clear() }
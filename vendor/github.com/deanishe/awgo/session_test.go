@@ -0,0 +1,95 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package aw
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func tempSession(t *testing.T) *Session {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "awgo-session")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return NewSession(dir)
+}
+
+func TestSessionStoreLoad(t *testing.T) {
+	s := tempSession(t)
+
+	type payload struct{ N int }
+	if err := s.Store("count", payload{N: 3}); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	var got payload
+	if !s.Load("count", &got) {
+		t.Fatal("Load returned false for a key that was just Stored")
+	}
+	if got.N != 3 {
+		t.Errorf("Load() = %+v, want N=3", got)
+	}
+}
+
+func TestSessionLoadMissingKey(t *testing.T) {
+	s := tempSession(t)
+
+	var got string
+	if s.Load("nope", &got) {
+		t.Fatal("Load returned true for a key that was never Stored")
+	}
+}
+
+func TestSessionSweepsStaleSessions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awgo-session")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Simulate a stale session's cache file left over from a previous run.
+	stale := filepath.Join(dir, "old-session-id.count.json")
+	if err := ioutil.WriteFile(stale, []byte("1"), 0600); err != nil {
+		t.Fatalf("write stale file: %v", err)
+	}
+
+	// Force a fresh session (no AW_SESSION_ID set).
+	os.Unsetenv(DefaultSessionName)
+	NewSession(dir)
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("stale session file was not swept")
+	}
+}
+
+func TestSessionNoDirIsNoop(t *testing.T) {
+	s := NewSession("")
+
+	if err := s.Store("count", 3); err != nil {
+		t.Fatalf("Store with no cache dir: %v", err)
+	}
+
+	var got int
+	if s.Load("count", &got) {
+		t.Error("Load returned true with no cache dir configured")
+	}
+}
+
+func TestNewItemAttachesSessionVar(t *testing.T) {
+	wf := New()
+	it := wf.NewItem("test")
+
+	name, value := wf.Session().Var()
+	if it.vars[name] != value {
+		t.Errorf("Item var %q = %q, want %q", name, it.vars[name], value)
+	}
+}
@@ -0,0 +1,67 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package aw
+
+// updateAvailableText is shown as the title of the item NotifyUpdate
+// appends to feedback when a newer version is available.
+const updateAvailableText = "Update available — press ↵ to install"
+
+// WithUpdateBadge sets the icon shown on the "update available" item that
+// Workflow.NotifyUpdate appends to feedback, in place of the default
+// IconUpdateAvailable. Pass it to New(), e.g.
+//
+//    wf := aw.New(aw.WithUpdateBadge(myIcon))
+func WithUpdateBadge(icon *Icon) Option {
+	return func(wf *Workflow) Option {
+		prev := wf.updateBadgeIcon
+		wf.updateBadgeIcon = icon
+		return WithUpdateBadge(prev)
+	}
+}
+
+// WithUpdater configures the Updater SendFeedback uses (together with
+// NotifyUpdate) to decide whether to append an "update available" item,
+// and registers the "workflow:update" Magic Action for it.
+func WithUpdater(u Updater) Option {
+	return func(wf *Workflow) Option {
+		prev := wf.updater
+		wf.updater = u
+		wf.MagicActions.Register(updateMagic{updater: u})
+		return WithUpdater(prev)
+	}
+}
+
+// NotifyUpdate enables or disables the "update available" item that
+// SendFeedback appends to the bottom of the feedback list when enabled
+// is true, an Updater is configured (via WithUpdater) and a newer
+// version is available. The check is purely local: it reads the cached
+// state written by the existing "workflow:update" Magic Action, so it
+// never makes a network call itself.
+func (wf *Workflow) NotifyUpdate(enabled bool) {
+	wf.notifyUpdate = enabled
+}
+
+// addUpdateItem appends the "update available" item to feedback, if
+// NotifyUpdate is enabled, an Updater is configured and it reports an
+// update is available. It's a no-op otherwise. SendFeedback calls this
+// just before sending its response to Alfred.
+func (wf *Workflow) addUpdateItem() {
+	if !wf.notifyUpdate || wf.updater == nil || !wf.updater.UpdateAvailable() {
+		return
+	}
+
+	icon := wf.updateBadgeIcon
+	if icon == nil {
+		icon = IconUpdateAvailable
+	}
+
+	wf.NewItem(updateAvailableText).
+		Valid(false).
+		Icon(icon).
+		UID("aw:update-available").
+		Autocomplete(wf.MagicPrefix + "update")
+}
@@ -0,0 +1,65 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package aw
+
+import "testing"
+
+type fakeUpdater struct {
+	available bool
+}
+
+func (u *fakeUpdater) CheckForUpdate() error { return nil }
+func (u *fakeUpdater) UpdateAvailable() bool { return u.available }
+func (u *fakeUpdater) Install() error        { return nil }
+
+func TestAddUpdateItemWhenAvailable(t *testing.T) {
+	wf := New(WithUpdater(&fakeUpdater{available: true}))
+	wf.NotifyUpdate(true)
+
+	wf.addUpdateItem()
+
+	if len(wf.items) != 1 {
+		t.Fatalf("len(items) = %d, want 1", len(wf.items))
+	}
+	if wf.items[0].icon != IconUpdateAvailable {
+		t.Errorf("item icon = %v, want IconUpdateAvailable", wf.items[0].icon)
+	}
+}
+
+func TestAddUpdateItemUsesCustomBadge(t *testing.T) {
+	badge := &Icon{Value: "update.png"}
+	wf := New(WithUpdater(&fakeUpdater{available: true}), WithUpdateBadge(badge))
+	wf.NotifyUpdate(true)
+
+	wf.addUpdateItem()
+
+	if len(wf.items) != 1 || wf.items[0].icon != badge {
+		t.Fatalf("addUpdateItem() did not use the custom badge icon")
+	}
+}
+
+func TestAddUpdateItemNoopWhenDisabled(t *testing.T) {
+	wf := New(WithUpdater(&fakeUpdater{available: true}))
+	// NotifyUpdate not called - defaults to disabled.
+
+	wf.addUpdateItem()
+
+	if len(wf.items) != 0 {
+		t.Errorf("len(items) = %d, want 0 when NotifyUpdate is disabled", len(wf.items))
+	}
+}
+
+func TestAddUpdateItemNoopWhenNoUpdateAvailable(t *testing.T) {
+	wf := New(WithUpdater(&fakeUpdater{available: false}))
+	wf.NotifyUpdate(true)
+
+	wf.addUpdateItem()
+
+	if len(wf.items) != 0 {
+		t.Errorf("len(items) = %d, want 0 when no update is available", len(wf.items))
+	}
+}
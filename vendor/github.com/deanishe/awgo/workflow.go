@@ -0,0 +1,144 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package aw
+
+import "os"
+
+// Option is a configuration function that customises a Workflow created
+// with New(). Each Option returns another Option that undoes its change,
+// so options can be temporarily overridden and restored.
+type Option func(wf *Workflow) Option
+
+// Updater checks for and installs newer releases of a workflow. The
+// "update" sub-package implements one backed by GitHub releases.
+type Updater interface {
+	// CheckForUpdate fetches the most recent available version and
+	// caches the result.
+	CheckForUpdate() error
+	// UpdateAvailable reports whether a newer version than the one
+	// currently installed is cached, without making a network call.
+	UpdateAvailable() bool
+	// Install downloads and installs the newest cached version.
+	Install() error
+}
+
+// Workflow is the main API for AwGo. It wraps the feedback sent to
+// Alfred and the workflow's cache and data directories. Other files in
+// this package (Magic Actions, the keychain, sessions, update checks)
+// add their own methods to it; the fields they use are declared here.
+type Workflow struct {
+	items []*Item
+
+	bundleID string
+	name     string
+	cacheDir string
+	dataDir  string
+	logFile  string
+
+	MagicPrefix  string
+	MagicActions MagicActions
+
+	keychain Keychainer
+	session  *Session
+
+	updater         Updater
+	notifyUpdate    bool
+	updateBadgeIcon *Icon
+}
+
+// New creates a Workflow configured from Alfred's "alfred_workflow_*"
+// environment variables, applying any Options passed to it.
+func New(opts ...Option) *Workflow {
+	wf := &Workflow{
+		bundleID:     os.Getenv("alfred_workflow_bundleid"),
+		name:         os.Getenv("alfred_workflow_name"),
+		cacheDir:     os.Getenv("alfred_workflow_cache"),
+		dataDir:      os.Getenv("alfred_workflow_data"),
+		MagicPrefix:  DefaultMagicPrefix,
+		MagicActions: MagicActions{},
+	}
+	wf.MagicActions.Register(DefaultMagicActions...)
+
+	for _, opt := range opts {
+		opt(wf)
+	}
+	return wf
+}
+
+// wf is the default Workflow used by the package-level convenience
+// functions (NewItem, SendFeedback, Args, etc.).
+var wf = New()
+
+// BundleID returns the workflow's bundle ID (Alfred's "alfred_workflow_bundleid").
+func (wf *Workflow) BundleID() string { return wf.bundleID }
+
+// Name returns the workflow's name (Alfred's "alfred_workflow_name").
+func (wf *Workflow) Name() string { return wf.name }
+
+// CacheDir returns the workflow's cache directory, which Alfred clears
+// when the user chooses "Clear Cache" in Alfred Preferences.
+func (wf *Workflow) CacheDir() string { return wf.cacheDir }
+
+// DataDir returns the workflow's data directory, which persists across
+// workflow updates and reinstalls.
+func (wf *Workflow) DataDir() string { return wf.dataDir }
+
+// NewItem adds and returns a new feedback Item. The Item automatically
+// carries the current Session's ID as a workflow variable, so Alfred
+// passes it back in on the next keystroke (see Session).
+func (wf *Workflow) NewItem(title string) *Item {
+	it := &Item{title: title, valid: true}
+	it.Var(wf.Session().Var())
+	wf.items = append(wf.items, it)
+	return it
+}
+
+// NewItem adds a new Item to the default Workflow's feedback.
+func NewItem(title string) *Item { return wf.NewItem(title) }
+
+// Filter filters the Workflow's current feedback Items against query,
+// discarding those whose text doesn't contain it (ignoring case), and
+// returns the survivors.
+func (wf *Workflow) Filter(query string) []*Item {
+	if query == "" {
+		return wf.items
+	}
+	kept := wf.items[:0]
+	for _, it := range wf.items {
+		if it.matches(query) {
+			kept = append(kept, it)
+		}
+	}
+	wf.items = kept
+	return wf.items
+}
+
+// Filter filters the default Workflow's feedback against query.
+func Filter(query string) []*Item { return wf.Filter(query) }
+
+// WarnEmpty adds title/subtitle as a non-valid Item if the Workflow
+// doesn't currently have any feedback Items.
+func (wf *Workflow) WarnEmpty(title, subtitle string) {
+	if len(wf.items) > 0 {
+		return
+	}
+	wf.NewItem(title).Subtitle(subtitle).Icon(IconWarning).Valid(false)
+}
+
+// WarnEmpty adds title/subtitle to the default Workflow's feedback if
+// it doesn't currently have any Items.
+func WarnEmpty(title, subtitle string) { wf.WarnEmpty(title, subtitle) }
+
+// SendFeedback sends the Workflow's current feedback Items to Alfred as
+// Script Filter JSON and exits the process.
+func (wf *Workflow) SendFeedback() {
+	wf.addUpdateItem()
+	send(wf.items)
+}
+
+// SendFeedback sends the default Workflow's feedback to Alfred.
+func SendFeedback() { wf.SendFeedback() }
@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2016 Dean Jackson <deanishe@deanishe.net>
+//
+// MIT Licence. See http://opensource.org/licenses/MIT
+//
+
+package aw
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// open opens path in its default application (Finder for a directory,
+// Console for a log file, etc.)
+func open(path string) error {
+	return exec.Command("open", path).Run()
+}
+
+// clearDir deletes the contents of dir, but not dir itself.
+func clearDir(dir string) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LogFile returns the path of the workflow's log file.
+func (wf *Workflow) LogFile() string {
+	if wf.logFile != "" {
+		return wf.logFile
+	}
+	return filepath.Join(wf.cacheDir, wf.name+".log")
+}
+
+// OpenLog opens the workflow's log file in the default app (usually Console).
+func (wf *Workflow) OpenLog() error { return open(wf.LogFile()) }
+
+// OpenLog opens the default Workflow's log file.
+func OpenLog() error { return wf.OpenLog() }
+
+// OpenCache opens the workflow's cache directory in the default app
+// (usually Finder).
+func (wf *Workflow) OpenCache() error { return open(wf.cacheDir) }
+
+// OpenCache opens the default Workflow's cache directory.
+func OpenCache() error { return wf.OpenCache() }
+
+// OpenData opens the workflow's data directory in the default app
+// (usually Finder).
+func (wf *Workflow) OpenData() error { return open(wf.dataDir) }
+
+// OpenData opens the default Workflow's data directory.
+func OpenData() error { return wf.OpenData() }
+
+// ClearCache deletes the contents of the workflow's cache directory.
+func (wf *Workflow) ClearCache() error { return clearDir(wf.cacheDir) }
+
+// ClearCache deletes the contents of the default Workflow's cache directory.
+func ClearCache() error { return wf.ClearCache() }
+
+// ClearData deletes the contents of the workflow's data directory.
+func (wf *Workflow) ClearData() error { return clearDir(wf.dataDir) }
+
+// ClearData deletes the contents of the default Workflow's data directory.
+func ClearData() error { return wf.ClearData() }
+
+// Reset deletes the contents of the workflow's cache and data directories.
+func (wf *Workflow) Reset() error {
+	if err := wf.ClearCache(); err != nil {
+		return err
+	}
+	return wf.ClearData()
+}
+
+// Reset deletes the contents of the default Workflow's cache and data
+// directories.
+func Reset() error { return wf.Reset() }
+
+// finishLog exits with a non-zero status if fatal is true, so Alfred
+// reports the run as failed. It logs nothing, and does nothing if fatal
+// is false.
+func finishLog(fatal bool) {
+	if fatal {
+		os.Exit(1)
+	}
+}